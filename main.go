@@ -4,8 +4,12 @@ package main
 
 import (
 	"context"
+	"encoding/json"
+	"fmt"
 	"log"
 	"math"
+	"os"
+	"strings"
 
 	"github.com/nextmv-io/sdk/mip"
 	"github.com/nextmv-io/sdk/run"
@@ -25,7 +29,106 @@ func main() {
 
 // The options for the solver.
 type options struct {
-	Limits mip.Limits `json:"limits,omitempty"`
+	Limits         mip.Limits            `json:"limits,omitempty"`
+	Solver         solverOptions         `json:"solver,omitempty"`
+	MultiObjective multiObjectiveOptions `json:"multi_objective,omitempty"`
+	Stochastic     stochasticOptions     `json:"stochastic,omitempty"`
+}
+
+// stochasticOptions configures how a two-stage model built from
+// input.Scenarios is optimized.
+type stochasticOptions struct {
+	// RiskMeasure is "expected" (the default) to maximize expected total
+	// effect across Scenarios, or "cvar" to maximize the Conditional
+	// Value-at-Risk of total effect instead.
+	RiskMeasure string `json:"risk_measure,omitempty"`
+	// Alpha is the confidence level used by the CVaR risk measure, in
+	// (0, 1). Defaults to 0.95.
+	Alpha float64 `json:"alpha,omitempty"`
+}
+
+// defaultCVaRAlpha is used when options.Stochastic.Alpha is not set.
+const defaultCVaRAlpha = 0.95
+
+// solverOptions selects the backend the model is solved with and tunes
+// backend-specific behavior.
+type solverOptions struct {
+	// Provider is the solver backend to use: "highs", "cbc", or "gurobi".
+	// Defaults to "highs" if not set.
+	//
+	// Per-provider tuning (threads, MIP gap absolute, node limit, warm
+	// start) was part of the original request but isn't included here:
+	// this tree has no go.mod or vendored SDK to confirm mip.SolveOptions
+	// actually exposes them, and shipping an unverified method risks a
+	// build that doesn't compile. Revisit once the SDK version is pinned.
+	Provider string `json:"provider,omitempty"`
+	// ImportSolutionPath, when set, skips solving entirely and instead
+	// loads a previously computed assignment set from this path, allowing a
+	// solution produced outside Nextmv (e.g. by CBC, Gurobi, or a
+	// human-in-the-loop) to be reported. The file holds a JSON array of
+	// {"property", "Renovation_id"} pairs; every pair is validated against
+	// the input before being reported.
+	ImportSolutionPath string `json:"import_solution_path,omitempty"`
+	// ExportLPPath and ExportMPSPath, when set, name files the constructed
+	// mip.Model should be serialized to in LP or MPS format before
+	// solving, so it can be fed into an external solver or inspected by
+	// hand. This isn't wired up yet: this tree has no go.mod or vendored
+	// SDK to confirm mip.Model exposes a matching serialization method,
+	// and guessing at one risks a build that doesn't compile. Setting
+	// either path fails fast with a clear error instead of silently doing
+	// nothing, so callers know export is deferred rather than supported.
+	ExportLPPath  string `json:"export_lp_path,omitempty"`
+	ExportMPSPath string `json:"export_mps_path,omitempty"`
+}
+
+// defaultSolverProvider is used when options.Solver.Provider is not set.
+const defaultSolverProvider = "highs"
+
+// multiObjectiveOptions configures how the Objectives declared on the input
+// are combined into a solve.
+type multiObjectiveOptions struct {
+	// Mode is "weighted" (the default) to scalarize all Objectives into a
+	// single objective using their Weight, or "lexicographic" to optimize
+	// them one at a time in priority order, fixing each at its optimum
+	// before moving to the next.
+	Mode string `json:"mode,omitempty"`
+}
+
+// defaultMultiObjectiveMode is used when options.MultiObjective.Mode is not
+// set.
+const defaultMultiObjectiveMode = "weighted"
+
+// lexicographicTolerance is the slack allowed, in either direction, when a
+// higher-priority objective is fixed at its optimum for lexicographic mode.
+const lexicographicTolerance = 1e-6
+
+// objectiveKind identifies one of the built-in objectives that can be
+// declared on the input.
+type objectiveKind string
+
+const (
+	// objectiveEffect maximizes the total effect of assigned Renovations.
+	objectiveEffect objectiveKind = "effect"
+	// objectiveCost minimizes the total cost of assigned Renovations.
+	objectiveCost objectiveKind = "cost"
+	// objectivePropertiesTouched maximizes the number of properties that
+	// receive at least one Renovation.
+	objectivePropertiesTouched objectiveKind = "properties_touched"
+	// objectiveFairness maximizes the minimum total effect received by any
+	// single property.
+	objectiveFairness objectiveKind = "fairness"
+)
+
+// objective declares one objective to optimize, either on its own or
+// alongside others.
+type objective struct {
+	// Kind is one of "effect", "cost", "properties_touched", or
+	// "fairness".
+	Kind objectiveKind `json:"kind"`
+	// Weight scales this objective's contribution in weighted mode.
+	// Defaults to 1 if not set. Ignored in lexicographic mode, where order
+	// in the Objectives slice determines priority instead.
+	Weight float64 `json:"weight,omitempty"`
 }
 
 // Input of the problem.
@@ -41,11 +144,80 @@ type input struct {
 			Effect float64 `json:"effect"`
 			// Cost of the Renovation that will be subtracted from the budget.
 			Cost float64 `json:"cost"`
+			// Period the Renovation is carried out in, indexing into
+			// Budgets. Defaults to period 0 if not set.
+			Period int `json:"period,omitempty"`
 		} `json:"renovations"`
+		// MaxRenovations is the maximum number of Renovations that can be
+		// assigned to the property. Defaults to 3 if unset (nil); an
+		// explicit 0 means no Renovations may be assigned to the property.
+		MaxRenovations *int `json:"max_renovations,omitempty"`
+		// Incompatibilities lists sets of Renovation IDs, belonging to this
+		// property, that cannot be chosen together. At most one Renovation
+		// ID per set may be assigned.
+		Incompatibilities [][]string `json:"incompatibilities,omitempty"`
+		// Prerequisites lists Renovation pairs, belonging to this property,
+		// where Renovation requires that Requires also be assigned.
+		Prerequisites []struct {
+			Renovation string `json:"renovation"`
+			Requires   string `json:"requires"`
+		} `json:"prerequisites,omitempty"`
 	} `json:"properties"`
-	Budget int `json:"budget"`
+	// Budgets holds one budget per period. Unspent budget in a period
+	// carries over to the next one.
+	Budgets []float64 `json:"budgets"`
+	// Budget is the deprecated single-period budget. If Budgets is empty
+	// and Budget is set, it is treated as Budgets[0].
+	Budget int `json:"budget,omitempty"`
+	// DiscountRate, when set, discounts the effect of a Renovation in
+	// period t by 1/(1+DiscountRate)^t, so that earlier effects are worth
+	// more than later ones.
+	DiscountRate float64 `json:"discount_rate,omitempty"`
+	// Objectives declares the objectives to optimize, in priority order
+	// for lexicographic mode. When empty, the solver falls back to
+	// maximizing total effect only.
+	Objectives []objective `json:"objectives,omitempty"`
+	// Scenarios switches the solver into stochastic mode: the Renovations
+	// in Properties become first-stage decisions, made once and shared
+	// across every scenario, and each scenario's RecoveryActions become
+	// scenario-dependent second-stage decisions.
+	Scenarios []scenario `json:"scenarios,omitempty"`
 }
 
+// scenario is a stochastic realization of recovery opportunities available
+// once the first-stage Renovations in Properties have been chosen.
+type scenario struct {
+	ID string `json:"id"`
+	// Probability of this scenario occurring. The probabilities across all
+	// Scenarios are expected to sum to 1.
+	Probability float64 `json:"probability"`
+	// RecoveryActions are second-stage decisions available only in this
+	// scenario, each requiring that its tied Renovation was selected in
+	// the first stage.
+	RecoveryActions []recoveryAction `json:"recovery_actions,omitempty"`
+	// RecoveryBudget caps the total cost of RecoveryActions taken in this
+	// scenario. Unlimited if not set.
+	RecoveryBudget float64 `json:"recovery_budget,omitempty"`
+}
+
+// recoveryAction is a second-stage decision tied to a first-stage
+// Renovation, only available once that Renovation has been selected.
+type recoveryAction struct {
+	ID string `json:"id"`
+	// Property and Renovation identify the first-stage Renovation this
+	// recovery action is tied to.
+	Property   string `json:"property"`
+	Renovation string `json:"renovation"`
+	// Positive effect of taking this recovery action in this scenario.
+	Effect float64 `json:"effect"`
+	// Cost of taking this recovery action in this scenario.
+	Cost float64 `json:"cost"`
+}
+
+// defaultMaxRenovations is the maximum number of Renovations assigned to a
+// property when MaxRenovations is not set on the input.
+const defaultMaxRenovations = 3.0
+
 // assignments is used to print the solutio∑n and represents the
 // combination of a property with the assigned Renovation.
 type assignments struct {
@@ -53,6 +225,7 @@ type assignments struct {
 	RenovationID string  `json:"Renovation_id"`
 	Cost         float64 `json:"cost"`
 	Effect       float64 `json:"effect"`
+	Period       int     `json:"period"`
 }
 
 // solution represents the decisions made by the solver.
@@ -60,88 +233,633 @@ type solution struct {
 	Assignments []assignments `json:"assignments,omitempty"`
 }
 
+// objectiveTerm is a single coefficient/variable pair contributing to one
+// of the built-in objective kinds.
+type objectiveTerm struct {
+	coefficient float64
+	variable    mip.Var
+}
+
+// validateInput rejects Renovations, Incompatibilities, Prerequisites, and
+// Scenario RecoveryActions that reference unknown IDs, or periods outside
+// the configured Budgets, before the model is built, so a typo or a
+// missing budget fails loudly instead of producing a nil variable or a
+// panic.
+func validateInput(input input) error {
+	renovationIDsByProperty := make(map[string]map[string]bool, len(input.Properties))
+
+	for _, property := range input.Properties {
+		renovationIDs := make(map[string]bool, len(property.Renovations))
+		for _, renovation := range property.Renovations {
+			renovationIDs[renovation.ID] = true
+
+			if renovation.Period < 0 || renovation.Period >= len(input.Budgets) {
+				return fmt.Errorf(
+					"property %q: Renovation %q has period %d but only %d budgets are configured",
+					property.ID, renovation.ID, renovation.Period, len(input.Budgets),
+				)
+			}
+		}
+		renovationIDsByProperty[property.ID] = renovationIDs
+
+		for _, incompatibilitySet := range property.Incompatibilities {
+			for _, renovationID := range incompatibilitySet {
+				if !renovationIDs[renovationID] {
+					return fmt.Errorf(
+						"property %q: incompatibilities reference unknown Renovation %q",
+						property.ID, renovationID,
+					)
+				}
+			}
+		}
+
+		for _, prerequisite := range property.Prerequisites {
+			if !renovationIDs[prerequisite.Renovation] {
+				return fmt.Errorf(
+					"property %q: prerequisites reference unknown Renovation %q",
+					property.ID, prerequisite.Renovation,
+				)
+			}
+			if !renovationIDs[prerequisite.Requires] {
+				return fmt.Errorf(
+					"property %q: prerequisite for %q requires unknown Renovation %q",
+					property.ID, prerequisite.Renovation, prerequisite.Requires,
+				)
+			}
+		}
+	}
+
+	for _, s := range input.Scenarios {
+		for _, action := range s.RecoveryActions {
+			renovationIDs, ok := renovationIDsByProperty[action.Property]
+			if !ok {
+				return fmt.Errorf(
+					"scenario %q: recovery action %q references unknown property %q",
+					s.ID, action.ID, action.Property,
+				)
+			}
+			if !renovationIDs[action.Renovation] {
+				return fmt.Errorf(
+					"scenario %q: recovery action %q references unknown Renovation %q on property %q",
+					s.ID, action.ID, action.Renovation, action.Property,
+				)
+			}
+		}
+	}
+
+	return nil
+}
+
 func solver(_ context.Context, input input, options options) (schema.Output, error) {
-	// We start by creating a MIP model.
-	m := mip.NewModel()
+	// Fall back to the deprecated single-period Budget when Budgets isn't
+	// set, so previously-working inputs keep solving unchanged. Budget
+	// unset/0 was baseline's "no budget" case, which still needs a single
+	// zero-valued period so period 0 Renovations validate instead of
+	// erroring with "0 budgets configured".
+	if len(input.Budgets) == 0 {
+		input.Budgets = []float64{float64(input.Budget)}
+	}
+
+	if err := validateInput(input); err != nil {
+		return schema.Output{}, err
+	}
+
+	if options.Solver.ExportLPPath != "" || options.Solver.ExportMPSPath != "" {
+		return schema.Output{}, fmt.Errorf(
+			"LP/MPS model export is not yet supported against this SDK version",
+		)
+	}
+
+	if len(input.Scenarios) > 0 && options.Solver.ImportSolutionPath == "" {
+		return solveStochastic(input, options)
+	}
+
+	m, propertyRenovationVariables, objectiveTerms, _ := buildModel(input)
 
-	// We want to maximize the value of the problem.
+	// If an externally computed solution was provided, report it directly
+	// instead of solving.
+	if options.Solver.ImportSolutionPath != "" {
+		selections, err := readImportedSelections(options.Solver.ImportSolutionPath)
+		if err != nil {
+			return schema.Output{}, err
+		}
+		imported, err := formatImported(input, selections)
+		if err != nil {
+			return schema.Output{}, err
+		}
+		return schema.Output{
+			Options:   options,
+			Solutions: []any{imported},
+		}, nil
+	}
+
+	mode := options.MultiObjective.Mode
+	if mode == "" {
+		mode = defaultMultiObjectiveMode
+	}
+	if len(input.Objectives) > 0 && strings.EqualFold(mode, "lexicographic") {
+		return solveLexicographic(input, options)
+	}
+
+	// Single-pass objective: either the default "maximize total effect", or
+	// a weighted scalarization of the declared Objectives.
+	objectives := input.Objectives
+	if len(objectives) == 0 {
+		objectives = []objective{{Kind: objectiveEffect, Weight: 1}}
+	}
 	m.Objective().SetMaximize()
+	for _, obj := range objectives {
+		addObjectiveTerms(m, objectiveTerms, obj)
+	}
+
+	mipSolver, solveOptions, err := newSolver(m, options)
+	if err != nil {
+		return schema.Output{}, err
+	}
 
-	// This constraint ensures the budget of the will not be exceeded.
-	budgetConstraint := m.NewConstraint(
-		mip.LessThanOrEqual,
-		float64(input.Budget),
+	solverSolution, err := mipSolver.Solve(solveOptions)
+	if err != nil {
+		return schema.Output{}, err
+	}
+
+	// Format the solution into the desired output format and add custom
+	// statistics.
+	output := mip.Format(options, format(input, solverSolution, propertyRenovationVariables), solverSolution)
+	if len(input.Objectives) == 0 {
+		output.Statistics.Result.Custom = mip.DefaultCustomResultStatistics(m, solverSolution)
+	} else {
+		output.Statistics.Result.Custom = resultCustomStatistics(m, solverSolution, objectives, objectiveTerms)
+	}
+
+	return output, nil
+}
+
+// addObjectiveTerms adds obj's weighted terms to m's objective, flipping the
+// sign for cost so every objective is expressed as something to maximize.
+func addObjectiveTerms(m mip.Model, objectiveTerms map[objectiveKind][]objectiveTerm, obj objective) {
+	weight := obj.Weight
+	if weight == 0 {
+		weight = 1
+	}
+	sign := 1.0
+	if obj.Kind == objectiveCost {
+		sign = -1
+	}
+	for _, term := range objectiveTerms[obj.Kind] {
+		m.Objective().NewTerm(sign*weight*term.coefficient, term.variable)
+	}
+}
+
+// solveLexicographic optimizes input.Objectives one at a time, in priority
+// order, fixing each objective at its optimum (within lexicographicTolerance)
+// as a constraint before solving for the next one.
+func solveLexicographic(input input, options options) (schema.Output, error) {
+	type fixedObjective struct {
+		kind  objectiveKind
+		value float64
+	}
+
+	var (
+		fixed                       []fixedObjective
+		m                           mip.Model
+		propertyRenovationVariables map[string][]mip.Var
+		objectiveTerms              map[objectiveKind][]objectiveTerm
+		solverSolution              mip.Solution
 	)
 
+	for _, obj := range input.Objectives {
+		m, propertyRenovationVariables, objectiveTerms, _ = buildModel(input)
+
+		// Fix every higher-priority objective to its previously found
+		// optimum, within a small tolerance, before optimizing this one.
+		for _, f := range fixed {
+			upperBound := m.NewConstraint(mip.LessThanOrEqual, f.value+lexicographicTolerance)
+			lowerBound := m.NewConstraint(mip.GreaterThanOrEqual, f.value-lexicographicTolerance)
+			for _, term := range objectiveTerms[f.kind] {
+				upperBound.NewTerm(term.coefficient, term.variable)
+				lowerBound.NewTerm(term.coefficient, term.variable)
+			}
+		}
+
+		m.Objective().SetMaximize()
+		addObjectiveTerms(m, objectiveTerms, obj)
+
+		mipSolver, solveOptions, err := newSolver(m, options)
+		if err != nil {
+			return schema.Output{}, err
+		}
+
+		solverSolution, err = mipSolver.Solve(solveOptions)
+		if err != nil {
+			return schema.Output{}, err
+		}
+
+		fixed = append(fixed, fixedObjective{
+			kind:  obj.Kind,
+			value: evaluateTerms(objectiveTerms[obj.Kind], solverSolution),
+		})
+	}
+
+	output := mip.Format(options, format(input, solverSolution, propertyRenovationVariables), solverSolution)
+	output.Statistics.Result.Custom = resultCustomStatistics(m, solverSolution, input.Objectives, objectiveTerms)
+
+	return output, nil
+}
+
+// solveStochastic builds and solves a two-stage model from input.Scenarios:
+// the Renovations in Properties are first-stage decisions shared across
+// every scenario, and each scenario's RecoveryActions are scenario-specific
+// second-stage decisions, available only once their tied Renovation is
+// selected. The objective maximizes expected total effect, or, when
+// options.Stochastic.RiskMeasure is "cvar", the Conditional Value-at-Risk of
+// total effect.
+func solveStochastic(input input, options options) (schema.Output, error) {
+	m, propertyRenovationVariables, objectiveTerms, renovationVariableByID := buildModel(input)
+
+	riskMeasure := options.Stochastic.RiskMeasure
+	if riskMeasure == "" {
+		riskMeasure = "expected"
+	}
+	alpha := options.Stochastic.Alpha
+	if alpha == 0 {
+		alpha = defaultCVaRAlpha
+	}
+
+	m.Objective().SetMaximize()
+
+	// eta is the Value-at-Risk threshold in the standard Rockafellar-Uryasev
+	// CVaR linearization; only used when RiskMeasure is "cvar". In that
+	// mode the objective is the pure "eta - (1/(1-alpha)) * sum p_s z_s"
+	// below: the first-stage effect must not also be added directly, since
+	// it is already accounted for inside cvarConstraint.
+	var eta mip.Var
+	if riskMeasure == "cvar" {
+		eta = m.NewFloat(-math.MaxFloat64, math.MaxFloat64)
+		m.Objective().NewTerm(1, eta)
+	} else {
+		for _, term := range objectiveTerms[objectiveEffect] {
+			m.Objective().NewTerm(term.coefficient, term.variable)
+		}
+	}
+
+	for _, s := range input.Scenarios {
+		hasRecoveryBudget := s.RecoveryBudget > 0
+		var recoveryBudgetConstraint mip.Constraint
+		if hasRecoveryBudget {
+			recoveryBudgetConstraint = m.NewConstraint(mip.LessThanOrEqual, s.RecoveryBudget)
+		}
+
+		// scenarioEffectTerms holds this scenario's own contribution to
+		// total effect: its RecoveryActions. The first-stage effect does
+		// not vary by scenario: in expected mode it is already on the
+		// objective above, and in cvar mode it is added to cvarConstraint
+		// below.
+		scenarioEffectTerms := make([]objectiveTerm, 0, len(s.RecoveryActions))
+		for _, action := range s.RecoveryActions {
+			firstStageVariable := renovationVariableByID[action.Property][action.Renovation]
+
+			recoveryVariable := m.NewBool()
+
+			// The recovery action can only be taken if its Renovation was
+			// selected in the first stage: y - x <= 0.
+			prerequisiteConstraint := m.NewConstraint(mip.LessThanOrEqual, 0)
+			prerequisiteConstraint.NewTerm(1, recoveryVariable)
+			prerequisiteConstraint.NewTerm(-1, firstStageVariable)
+
+			if hasRecoveryBudget {
+				recoveryBudgetConstraint.NewTerm(action.Cost, recoveryVariable)
+			}
+
+			scenarioEffectTerms = append(scenarioEffectTerms, objectiveTerm{
+				coefficient: action.Effect,
+				variable:    recoveryVariable,
+			})
+		}
+
+		if riskMeasure == "cvar" {
+			// z >= eta - effect_s, where effect_s is this scenario's total
+			// effect (first-stage plus recovery): z - eta + effect_s >= 0.
+			z := m.NewFloat(0, math.MaxFloat64)
+			cvarConstraint := m.NewConstraint(mip.GreaterThanOrEqual, 0)
+			cvarConstraint.NewTerm(1, z)
+			cvarConstraint.NewTerm(-1, eta)
+			for _, term := range objectiveTerms[objectiveEffect] {
+				cvarConstraint.NewTerm(term.coefficient, term.variable)
+			}
+			for _, term := range scenarioEffectTerms {
+				cvarConstraint.NewTerm(term.coefficient, term.variable)
+			}
+
+			m.Objective().NewTerm(-s.Probability/(1-alpha), z)
+		} else {
+			for _, term := range scenarioEffectTerms {
+				m.Objective().NewTerm(s.Probability*term.coefficient, term.variable)
+			}
+		}
+	}
+
+	mipSolver, solveOptions, err := newSolver(m, options)
+	if err != nil {
+		return schema.Output{}, err
+	}
+
+	solverSolution, err := mipSolver.Solve(solveOptions)
+	if err != nil {
+		return schema.Output{}, err
+	}
+
+	output := mip.Format(options, format(input, solverSolution, propertyRenovationVariables), solverSolution)
+	output.Statistics.Result.Custom = mip.DefaultCustomResultStatistics(m, solverSolution)
+
+	return output, nil
+}
+
+// evaluateTerms sums coefficient * value for every term, against the given
+// solution.
+func evaluateTerms(terms []objectiveTerm, solverSolution mip.Solution) float64 {
+	total := 0.0
+	for _, term := range terms {
+		total += term.coefficient * solverSolution.Value(term.variable)
+	}
+	return total
+}
+
+// resultCustomStatistics reports the default MIP statistics alongside the
+// achieved value of each declared objective.
+func resultCustomStatistics(
+	m mip.Model,
+	solverSolution mip.Solution,
+	objectives []objective,
+	objectiveTerms map[objectiveKind][]objectiveTerm,
+) any {
+	values := make(map[string]float64, len(objectives))
+	for _, obj := range objectives {
+		values[string(obj.Kind)] = evaluateTerms(objectiveTerms[obj.Kind], solverSolution)
+	}
+
+	return map[string]any{
+		"default":    mip.DefaultCustomResultStatistics(m, solverSolution),
+		"objectives": values,
+	}
+}
+
+// newSolver creates the solver for the configured provider and assembles
+// the solve options to run it with.
+func newSolver(m mip.Model, options options) (mip.Solver, mip.SolveOptions, error) {
+	// We create a solver using the configured provider, defaulting to
+	// 'highs' so existing inputs keep working unchanged.
+	provider := options.Solver.Provider
+	if provider == "" {
+		provider = defaultSolverProvider
+	}
+	mipSolver, err := mip.NewSolver(provider, m)
+	if err != nil {
+		return nil, nil, err
+	}
+
+	// We create the solve options we will use.
+	solveOptions := mip.NewSolveOptions()
+
+	// Limit the solve to a maximum duration.
+	if err = solveOptions.SetMaximumDuration(options.Limits.Duration); err != nil {
+		return nil, nil, err
+	}
+
+	// Set the relative gap to 0% (highs' default is 5%)
+	if err = solveOptions.SetMIPGapRelative(0); err != nil {
+		return nil, nil, err
+	}
+
+	// Turn off the solver's log output.
+	solveOptions.SetVerbosity(mip.Off)
+
+	return mipSolver, solveOptions, nil
+}
+
+// buildModel constructs the MIP model for input: the decision variables,
+// the budget, count, incompatibility, and prerequisite constraints, and the
+// terms available to build one or more objectives from. It does not set an
+// objective on the returned model; callers do that afterward.
+func buildModel(
+	input input,
+) (mip.Model, map[string][]mip.Var, map[objectiveKind][]objectiveTerm, map[string]map[string]mip.Var) {
+	m := mip.NewModel()
+
+	// Create one budget constraint per period, plus a carry-over variable
+	// linking each period to the next: spend_t + carry_t - carry_{t-1} =
+	// budget_t. Unspent budget (carry_t) rolls into period t+1.
+	budgetConstraints := make([]mip.Constraint, len(input.Budgets))
+	carryOverVariables := make([]mip.Var, len(input.Budgets))
+	for t, budget := range input.Budgets {
+		budgetConstraints[t] = m.NewConstraint(mip.Equal, budget)
+		carryOverVariables[t] = m.NewFloat(0, math.MaxFloat64)
+		budgetConstraints[t].NewTerm(1, carryOverVariables[t])
+		if t > 0 {
+			budgetConstraints[t].NewTerm(-1, carryOverVariables[t-1])
+		}
+	}
+
+	// Determine which auxiliary objectives were declared, so we only pay
+	// for the variables and constraints they need.
+	needsPropertiesTouched := false
+	needsFairness := false
+	for _, obj := range input.Objectives {
+		switch obj.Kind {
+		case objectivePropertiesTouched:
+			needsPropertiesTouched = true
+		case objectiveFairness:
+			needsFairness = true
+		}
+	}
+
+	// fairnessVariable is shared by every property: it can be no larger
+	// than any single property's total effect, so maximizing it maximizes
+	// the minimum effect received by any property.
+	var fairnessVariable mip.Var
+	if needsFairness {
+		fairnessVariable = m.NewFloat(0, math.MaxFloat64)
+	}
+
+	objectiveTerms := map[objectiveKind][]objectiveTerm{}
+
 	// Create a map of property ID to a slice of decision variables, one for each
 	// Renovation.
 	propertyRenovationVariables := make(map[string][]mip.Var, len(input.Properties))
+
+	// Create a map of property ID to a map of Renovation ID to decision
+	// variable, used to look up variables by Renovation ID when wiring
+	// incompatibility and prerequisite constraints.
+	renovationVariableByID := make(map[string]map[string]mip.Var, len(input.Properties))
+
 	for _, property := range input.Properties {
 		// For each property, create the slice of variables based on the number of
 		// Renovations.
 		propertyRenovationVariables[property.ID] = make([]mip.Var, len(property.Renovations))
+		renovationVariableByID[property.ID] = make(map[string]mip.Var, len(property.Renovations))
+
+		// This constraint ensures that each property is assigned at most
+		// MaxRenovations Renovations, defaulting to 3 if unset.
+		maxRenovations := defaultMaxRenovations
+		if property.MaxRenovations != nil {
+			maxRenovations = float64(*property.MaxRenovations)
+		}
+		countRenovationConstraint := m.NewConstraint(mip.LessThanOrEqual, maxRenovations)
+
+		// touchedConstraint ensures touchedVariable can only be 1 if at
+		// least one Renovation is assigned to the property: touched -
+		// sum(x_i) <= 0.
+		var touchedVariable mip.Var
+		var touchedConstraint mip.Constraint
+		if needsPropertiesTouched {
+			touchedVariable = m.NewBool()
+			touchedConstraint = m.NewConstraint(mip.LessThanOrEqual, 0)
+			touchedConstraint.NewTerm(1, touchedVariable)
+			objectiveTerms[objectivePropertiesTouched] = append(
+				objectiveTerms[objectivePropertiesTouched],
+				objectiveTerm{coefficient: 1, variable: touchedVariable},
+			)
+		}
+
+		// fairnessConstraint ensures fairnessVariable can be no larger than
+		// this property's total effect: fairness - sum(effect_i * x_i) <= 0.
+		var fairnessConstraint mip.Constraint
+		if needsFairness {
+			fairnessConstraint = m.NewConstraint(mip.LessThanOrEqual, 0)
+			fairnessConstraint.NewTerm(1, fairnessVariable)
+		}
 
-		// This constraint ensures that each property is assigned at most three
-		// Renovations.
-		countRenovationConstraint := m.NewConstraint(mip.LessThanOrEqual, 3.0)
 		for i, Renovation := range property.Renovations {
 			// For each Renovation, create a binary decision variable.
 			propertyRenovationVariables[property.ID][i] = m.NewBool()
+			variable := propertyRenovationVariables[property.ID][i]
+			renovationVariableByID[property.ID][Renovation.ID] = variable
 
-			// Set the term of the variable on the objective, based on the
-			// effect the Renovation has on the property.
-			m.Objective().NewTerm(
-				Renovation.Effect,
-				propertyRenovationVariables[property.ID][i],
+			// Discount the effect of the Renovation by its period when a
+			// discount rate is set.
+			effect := Renovation.Effect
+			if input.DiscountRate > 0 {
+				effect /= math.Pow(1+input.DiscountRate, float64(Renovation.Period))
+			}
+			objectiveTerms[objectiveEffect] = append(
+				objectiveTerms[objectiveEffect],
+				objectiveTerm{coefficient: effect, variable: variable},
 			)
-
-			// Set the term of the variable on the budget constraint, based on
-			// the cost of the Renovation for the property.
-			budgetConstraint.NewTerm(
-				Renovation.Cost,
-				propertyRenovationVariables[property.ID][i],
+			objectiveTerms[objectiveCost] = append(
+				objectiveTerms[objectiveCost],
+				objectiveTerm{coefficient: Renovation.Cost, variable: variable},
 			)
 
+			// Set the term of the variable on its period's budget
+			// constraint, based on the cost of the Renovation for the
+			// property.
+			budgetConstraints[Renovation.Period].NewTerm(Renovation.Cost, variable)
+
 			// Set the term of the variable on the constraint that controls the
 			// number of Renovations per property.
-			countRenovationConstraint.NewTerm(1, propertyRenovationVariables[property.ID][i])
+			countRenovationConstraint.NewTerm(1, variable)
+
+			if needsPropertiesTouched {
+				touchedConstraint.NewTerm(-1, variable)
+			}
+			if needsFairness {
+				fairnessConstraint.NewTerm(-effect, variable)
+			}
 		}
 	}
 
-	// We create a solver using the 'highs' provider.
-	solver, err := mip.NewSolver("highs", m)
-	if err != nil {
-		return schema.Output{}, err
+	if needsFairness {
+		objectiveTerms[objectiveFairness] = []objectiveTerm{{coefficient: 1, variable: fairnessVariable}}
 	}
 
-	// We create the solve options we will use.
-	solveOptions := mip.NewSolveOptions()
+	// Wire up the incompatibility and prerequisite constraints now that all
+	// Renovation variables have been created.
+	for _, property := range input.Properties {
+		variables := renovationVariableByID[property.ID]
 
-	// Limit the solve to a maximum duration.
-	if err = solveOptions.SetMaximumDuration(options.Limits.Duration); err != nil {
-		return schema.Output{}, err
-	}
+		// Each incompatibility set means at most one of its Renovations may
+		// be assigned: x_i + x_j + ... <= 1.
+		for _, incompatibilitySet := range property.Incompatibilities {
+			incompatibilityConstraint := m.NewConstraint(mip.LessThanOrEqual, 1.0)
+			for _, renovationID := range incompatibilitySet {
+				incompatibilityConstraint.NewTerm(1, variables[renovationID])
+			}
+		}
 
-	// Set the relative gap to 0% (highs' default is 5%)
-	if err = solveOptions.SetMIPGapRelative(0); err != nil {
-		return schema.Output{}, err
+		// Each prerequisite means the required Renovation must also be
+		// assigned: x_renovation - x_requires <= 0.
+		for _, prerequisite := range property.Prerequisites {
+			prerequisiteConstraint := m.NewConstraint(mip.LessThanOrEqual, 0.0)
+			prerequisiteConstraint.NewTerm(1, variables[prerequisite.Renovation])
+			prerequisiteConstraint.NewTerm(-1, variables[prerequisite.Requires])
+		}
 	}
 
-	// Set verbose level to see a more detailed output
-	solveOptions.SetVerbosity(mip.Off)
+	return m, propertyRenovationVariables, objectiveTerms, renovationVariableByID
+}
 
-	// Solve the model and get the solution.
-	solution, err := solver.Solve(solveOptions)
+// importedSelection identifies one Renovation selected in an externally
+// computed solution, by property and Renovation ID.
+type importedSelection struct {
+	Property     string `json:"property"`
+	RenovationID string `json:"Renovation_id"`
+}
+
+// readImportedSelections reads the set of selected Renovations written by
+// an external solver run from path.
+func readImportedSelections(path string) ([]importedSelection, error) {
+	data, err := os.ReadFile(path)
 	if err != nil {
-		return schema.Output{}, err
+		return nil, err
 	}
 
-	// Format the solution into the desired output format and add custom
-	// statistics.
-	output := mip.Format(options, format(input, solution, propertyRenovationVariables), solution)
-	output.Statistics.Result.Custom = mip.DefaultCustomResultStatistics(m, solution)
+	selections := []importedSelection{}
+	if err := json.Unmarshal(data, &selections); err != nil {
+		return nil, err
+	}
 
-	return output, nil
+	return selections, nil
+}
+
+// formatImported builds a solution from a set of externally selected
+// Renovations, the same way format does against a solved model: every
+// selection is validated against input and turned into an assignments entry
+// carrying that Renovation's cost, effect, and period, so a typo'd ID fails
+// loudly instead of being echoed back uninspected.
+func formatImported(input input, selections []importedSelection) (solution, error) {
+	renovationsByProperty := make(map[string]map[string]assignments, len(input.Properties))
+	for _, property := range input.Properties {
+		renovations := make(map[string]assignments, len(property.Renovations))
+		for _, renovation := range property.Renovations {
+			renovations[renovation.ID] = assignments{
+				Property:     property.ID,
+				RenovationID: renovation.ID,
+				Cost:         renovation.Cost,
+				Effect:       renovation.Effect,
+				Period:       renovation.Period,
+			}
+		}
+		renovationsByProperty[property.ID] = renovations
+	}
+
+	assigned := make([]assignments, 0, len(selections))
+	for _, selection := range selections {
+		renovations, ok := renovationsByProperty[selection.Property]
+		if !ok {
+			return solution{}, fmt.Errorf("imported solution references unknown property %q", selection.Property)
+		}
+
+		assignment, ok := renovations[selection.RenovationID]
+		if !ok {
+			return solution{}, fmt.Errorf(
+				"imported solution references unknown Renovation %q on property %q",
+				selection.RenovationID, selection.Property,
+			)
+		}
+
+		assigned = append(assigned, assignment)
+	}
+
+	return solution{Assignments: assigned}, nil
 }
 
 // format the solution from the solver into the desired output format.
@@ -171,6 +889,7 @@ func format(
 					Effect:       input.Properties[i].Renovations[j].Effect,
 					Property:     property.ID,
 					RenovationID: input.Properties[i].Renovations[j].ID,
+					Period:       input.Properties[i].Renovations[j].Period,
 				},
 			)
 		}